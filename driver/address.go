@@ -0,0 +1,107 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+)
+
+// resolveStaticAddress returns the external IPv4 address value to assign to
+// the instance's NAT spec, reserving a new one or looking up an existing one
+// as needed. It returns an empty string if no static address was requested.
+func (c *YCClient) resolveStaticAddress(d *Driver) (string, error) {
+	switch {
+	case d.Address != "":
+		address, err := c.getAddress(d.Address)
+		if err != nil {
+			return "", fmt.Errorf("Error while looking up reserved address %q: %s", d.Address, err)
+		}
+		return address.GetExternalIpv4Address().GetAddress(), nil
+
+	case d.ReserveStaticAddress:
+		addressID, err := c.createAddress(d)
+		if err != nil {
+			return "", fmt.Errorf("Error while reserving static address: %s", err)
+		}
+		d.CreatedAddressID = addressID
+
+		address, err := c.getAddress(addressID)
+		if err != nil {
+			return "", fmt.Errorf("Error while looking up reserved address %q: %s", addressID, err)
+		}
+		return address.GetExternalIpv4Address().GetAddress(), nil
+
+	default:
+		return "", nil
+	}
+}
+
+func (c *YCClient) getAddress(addressID string) (*vpc.Address, error) {
+	return c.sdk.VPC().Address().Get(context.Background(), &vpc.GetAddressRequest{
+		AddressId: addressID,
+	})
+}
+
+func (c *YCClient) createAddress(d *Driver) (string, error) {
+	ctx := context.Background()
+
+	name := d.MachineName + "-address"
+	log.Infof("Reserving static address %q in zone %q", name, d.Zone)
+
+	op, err := c.sdk.WrapOperation(c.sdk.VPC().Address().Create(ctx, &vpc.CreateAddressRequest{
+		FolderId: d.FolderID,
+		Name:     name,
+		AddressSpec: &vpc.CreateAddressRequest_ExternalIpv4AddressSpec{
+			ExternalIpv4AddressSpec: &vpc.ExternalIpv4AddressSpec{
+				ZoneId: d.Zone,
+			},
+		},
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	protoMetadata, err := op.Metadata()
+	if err != nil {
+		return "", err
+	}
+
+	md, ok := protoMetadata.(*vpc.CreateAddressMetadata)
+	if !ok {
+		return "", fmt.Errorf("could not get Address ID from create operation metadata")
+	}
+
+	if err = op.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	return md.AddressId, nil
+}
+
+// removeStaticAddress releases the static address this driver reserved, if
+// any. Addresses the user supplied via --yandex-address are left alone.
+func (c *YCClient) removeStaticAddress(d *Driver) error {
+	if d.CreatedAddressID == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	log.Infof("Releasing static address %q", d.CreatedAddressID)
+
+	op, err := c.sdk.WrapOperation(c.sdk.VPC().Address().Delete(ctx, &vpc.DeleteAddressRequest{
+		AddressId: d.CreatedAddressID,
+	}))
+	if err != nil {
+		return fmt.Errorf("Error while releasing address: %s", err)
+	}
+	if err = op.Wait(ctx); err != nil {
+		return fmt.Errorf("Error while releasing address: %s", err)
+	}
+
+	d.CreatedAddressID = ""
+
+	return nil
+}
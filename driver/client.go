@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/c2h5oh/datasize"
 	"github.com/docker/machine/libmachine/log"
@@ -16,11 +17,19 @@ const StandardImagesFolderID = "standard-images"
 
 type YCClient struct {
 	sdk *ycsdk.SDK
+
+	// CredentialsSource records where the client's credentials were
+	// resolved from, surfaced to users via Driver.GetState() debug output.
+	CredentialsSource credentialsSource
 }
 
-func (c *YCClient) createInstance(d *Driver) error {
+func (c *YCClient) createInstance(d *Driver) (err error) {
 	ctx := context.Background()
 
+	if err := validateSpec(d); err != nil {
+		return err
+	}
+
 	imageID := d.ImageID
 	if imageID == "" {
 		var err error
@@ -32,7 +41,33 @@ func (c *YCClient) createInstance(d *Driver) error {
 
 	log.Infof("Use image with ID %q from folder ID %q", imageID, d.ImageFolderID)
 
-	request := prepareInstanceCreateRequest(d, imageID)
+	// docker-machine only persists the host's config.json after Create()
+	// returns successfully, so any network, subnet, or static address
+	// provisioned below would otherwise be orphaned: Remove() run from a
+	// later process would reload a Driver with these IDs still empty.
+	// Roll them back ourselves on any failure before the instance itself
+	// exists to take ownership of them.
+	defer func() {
+		if err != nil && d.InstanceID == "" {
+			if rollbackErr := errors.Join(c.removeStaticAddress(d), c.removeNetworking(d)); rollbackErr != nil {
+				log.Errorf("Error cleaning up after failed instance creation: %s", rollbackErr)
+			}
+		}
+	}()
+
+	if err = c.ensureNetworking(d); err != nil {
+		return err
+	}
+
+	staticAddress, err := c.resolveStaticAddress(d)
+	if err != nil {
+		return err
+	}
+
+	request, err := prepareInstanceCreateRequest(d, imageID, staticAddress)
+	if err != nil {
+		return err
+	}
 
 	op, err := c.sdk.WrapOperation(c.sdk.Compute().Instance().Create(ctx, request))
 	if err != nil {
@@ -51,6 +86,12 @@ func (c *YCClient) createInstance(d *Driver) error {
 
 	d.InstanceID = md.InstanceId
 
+	defer func() {
+		if err != nil {
+			c.captureSerialLog(d)
+		}
+	}()
+
 	log.Infof("Waiting for Instance with ID %q", d.InstanceID)
 	if err = op.Wait(ctx); err != nil {
 		return fmt.Errorf("Error while waiting operation to create instance: %s", err)
@@ -71,9 +112,11 @@ func (c *YCClient) createInstance(d *Driver) error {
 	return err
 }
 
-func prepareInstanceCreateRequest(d *Driver, imageID string) *compute.CreateInstanceRequest {
-	// TODO support static address assignment
-	// TODO additional disks
+func prepareInstanceCreateRequest(d *Driver, imageID, staticAddress string) (*compute.CreateInstanceRequest, error) {
+	additionalDisks, err := d.ParsedAdditionalDisks()
+	if err != nil {
+		return nil, err
+	}
 
 	request := &compute.CreateInstanceRequest{
 		FolderId:   d.FolderID,
@@ -84,6 +127,7 @@ func prepareInstanceCreateRequest(d *Driver, imageID string) *compute.CreateInst
 			Cores:        int64(d.Cores),
 			CoreFraction: int64(d.CoreFraction),
 			Memory:       toBytes(d.Memory),
+			Gpus:         int64(d.GPUs),
 		},
 		BootDiskSpec: &compute.AttachedDiskSpec{
 			AutoDelete: true,
@@ -97,7 +141,8 @@ func prepareInstanceCreateRequest(d *Driver, imageID string) *compute.CreateInst
 				},
 			},
 		},
-		Labels: d.ParsedLabels(),
+		SecondaryDiskSpecs: additionalDisks,
+		Labels:             d.ParsedLabels(),
 		NetworkInterfaceSpecs: []*compute.NetworkInterfaceSpec{
 			{
 				SubnetId:             d.SubnetID,
@@ -113,33 +158,43 @@ func prepareInstanceCreateRequest(d *Driver, imageID string) *compute.CreateInst
 	if d.Nat {
 		request.NetworkInterfaceSpecs[0].PrimaryV4AddressSpec.OneToOneNatSpec = &compute.OneToOneNatSpec{
 			IpVersion: compute.IpVersion_IPV4,
+			Address:   staticAddress,
+		}
+	}
+
+	if d.PlacementGroupID != "" {
+		request.PlacementPolicy = &compute.PlacementPolicy{
+			PlacementGroupId: d.PlacementGroupID,
 		}
 	}
 
-	return request
+	return request, nil
 }
 
+// credentialsSource describes where NewYCClient resolved a Credentials value
+// from, for surfacing to users via Driver.GetState() debug output.
+type credentialsSource string
+
+const (
+	credentialsSourceTokenFlag      credentialsSource = "--yandex-token"
+	credentialsSourceSAKeyFileFlag  credentialsSource = "--yandex-sa-key-file"
+	credentialsSourceTokenEnv       credentialsSource = "YC_TOKEN environment variable"
+	credentialsSourceSAKeyFileEnv   credentialsSource = "YC_SERVICE_ACCOUNT_KEY_FILE environment variable"
+	credentialsSourceInstanceSAMeta credentialsSource = "instance service account metadata"
+)
+
 func NewYCClient(d *Driver) (*YCClient, error) {
 	if d.Token != "" && d.ServiceAccountKeyFile != "" {
 		return nil, errors.New("one of token or service account key file must be specified, not both")
 	}
 
-	var credentials ycsdk.Credentials
-	switch {
-	case d.Token != "":
-		credentials = ycsdk.OAuthToken(d.Token)
-	case d.ServiceAccountKeyFile != "":
-		key, err := iamkey.ReadFromJSONFile(d.ServiceAccountKeyFile)
-		if err != nil {
-			return nil, err
-		}
-
-		credentials, err = ycsdk.ServiceAccountKey(key)
-		if err != nil {
-			return nil, err
-		}
+	credentials, source, err := resolveCredentials(d)
+	if err != nil {
+		return nil, err
 	}
 
+	log.Debugf("Resolved Yandex.Cloud credentials from %s", source)
+
 	config := ycsdk.Config{
 		Credentials: credentials,
 	}
@@ -154,10 +209,136 @@ func NewYCClient(d *Driver) (*YCClient, error) {
 	}
 
 	return &YCClient{
-		sdk: sdk,
+		sdk:               sdk,
+		CredentialsSource: source,
 	}, nil
 }
 
+// resolveCredentials follows the chain docker-machine users expect:
+// explicit flags first, then the matching environment variables, and
+// finally the instance service account available when running on a
+// Yandex.Cloud VM itself.
+func resolveCredentials(d *Driver) (ycsdk.Credentials, credentialsSource, error) {
+	switch {
+	case d.Token != "":
+		return ycsdk.OAuthToken(d.Token), credentialsSourceTokenFlag, nil
+	case d.ServiceAccountKeyFile != "":
+		credentials, err := serviceAccountCredentials(d.ServiceAccountKeyFile)
+		return credentials, credentialsSourceSAKeyFileFlag, err
+	case os.Getenv("YC_TOKEN") != "":
+		return ycsdk.OAuthToken(os.Getenv("YC_TOKEN")), credentialsSourceTokenEnv, nil
+	case os.Getenv("YC_SERVICE_ACCOUNT_KEY_FILE") != "":
+		credentials, err := serviceAccountCredentials(os.Getenv("YC_SERVICE_ACCOUNT_KEY_FILE"))
+		return credentials, credentialsSourceSAKeyFileEnv, err
+	default:
+		return ycsdk.InstanceServiceAccount(), credentialsSourceInstanceSAMeta, nil
+	}
+}
+
+func serviceAccountCredentials(keyFile string) (ycsdk.Credentials, error) {
+	key, err := iamkey.ReadFromJSONFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return ycsdk.ServiceAccountKey(key)
+}
+
+// fetchSerialConsoleOutput retrieves the instance's serial console output,
+// shared by the best-effort capture on create failure and by
+// Driver.DebugSerialConsole.
+func (c *YCClient) fetchSerialConsoleOutput(d *Driver) (string, error) {
+	resp, err := c.sdk.Compute().Instance().GetSerialPortOutput(context.Background(), &compute.GetInstanceSerialPortOutputRequest{
+		InstanceId: d.InstanceID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Contents, nil
+}
+
+// captureSerialLog fetches the instance's serial console output and logs it
+// at debug level, optionally persisting it to --yandex-serial-log-file. It
+// is best-effort: failures to fetch or write the log are logged rather than
+// returned, so they don't mask the original provisioning error.
+func (c *YCClient) captureSerialLog(d *Driver) {
+	if d.InstanceID == "" {
+		return
+	}
+
+	output, err := c.fetchSerialConsoleOutput(d)
+	if err != nil {
+		log.Debugf("Could not fetch serial console output for instance %q: %s", d.InstanceID, err)
+		return
+	}
+
+	log.Debugf("Serial console output for instance %q:\n%s", d.InstanceID, output)
+
+	if d.SerialLogFile == "" {
+		return
+	}
+
+	if err := os.WriteFile(d.SerialLogFile, []byte(output), 0644); err != nil {
+		log.Debugf("Could not write serial console output to %q: %s", d.SerialLogFile, err)
+	}
+}
+
+func (c *YCClient) getInstance(d *Driver) (*compute.Instance, error) {
+	return c.sdk.Compute().Instance().Get(context.Background(), &compute.GetInstanceRequest{
+		InstanceId: d.InstanceID,
+	})
+}
+
+func (c *YCClient) removeInstance(d *Driver) error {
+	ctx := context.Background()
+
+	op, err := c.sdk.WrapOperation(c.sdk.Compute().Instance().Delete(ctx, &compute.DeleteInstanceRequest{
+		InstanceId: d.InstanceID,
+	}))
+	if err != nil {
+		return fmt.Errorf("Error while requesting API to delete instance: %s", err)
+	}
+
+	if err = op.Wait(ctx); err != nil {
+		return err
+	}
+
+	// The static address and the ephemeral network/subnet are independent
+	// resources; attempt to remove both even if one fails, so a failure on
+	// one doesn't leak the other.
+	addressErr := c.removeStaticAddress(d)
+	networkingErr := c.removeNetworking(d)
+
+	return errors.Join(addressErr, networkingErr)
+}
+
+func (c *YCClient) startInstance(d *Driver) error {
+	ctx := context.Background()
+
+	op, err := c.sdk.WrapOperation(c.sdk.Compute().Instance().Start(ctx, &compute.StartInstanceRequest{
+		InstanceId: d.InstanceID,
+	}))
+	if err != nil {
+		return fmt.Errorf("Error while requesting API to start instance: %s", err)
+	}
+
+	return op.Wait(ctx)
+}
+
+func (c *YCClient) stopInstance(d *Driver) error {
+	ctx := context.Background()
+
+	op, err := c.sdk.WrapOperation(c.sdk.Compute().Instance().Stop(ctx, &compute.StopInstanceRequest{
+		InstanceId: d.InstanceID,
+	}))
+	if err != nil {
+		return fmt.Errorf("Error while requesting API to stop instance: %s", err)
+	}
+
+	return op.Wait(ctx)
+}
+
 func (c *YCClient) getImageIDFromFolder(familyName, lookupFolderID string) (string, error) {
 	image, err := c.sdk.Compute().Image().GetLatestByFamily(context.Background(), &compute.GetImageLatestByFamilyRequest{
 		FolderId: lookupFolderID,
@@ -0,0 +1,549 @@
+package driver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/mcnflag"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+)
+
+const (
+	defaultZone          = "ru-central1-a"
+	defaultPlatformID    = "standard-v1"
+	defaultCores         = 2
+	defaultCoreFraction  = 100
+	defaultMemory        = 2
+	defaultDiskType      = "network-hdd"
+	defaultDiskSize      = 20
+	defaultImageFamily   = "ubuntu-1804-lts"
+	defaultImageFolderID = StandardImagesFolderID
+	defaultSSHPort       = 22
+	defaultSSHUser       = "yc-user"
+	defaultSubnetCIDR    = "192.168.0.0/24"
+)
+
+// Driver is the docker-machine driver for Yandex.Cloud Compute instances.
+type Driver struct {
+	*drivers.BaseDriver
+
+	Token                 string
+	ServiceAccountKeyFile string
+	Endpoint              string
+
+	FolderID      string
+	Zone          string
+	PlatformID    string
+	Cores         int
+	CoreFraction  int
+	Memory        int
+	DiskType      string
+	DiskSize      int
+	ImageID       string
+	ImageFamily   string
+	ImageFolderID string
+
+	SubnetID      string
+	SubnetCIDR    string
+	Nat           bool
+	UseIPv6       bool
+	UseInternalIP bool
+
+	Address              string
+	ReserveStaticAddress bool
+
+	CreatedNetworkID string
+	CreatedSubnetID  string
+	CreatedAddressID string
+
+	Preemptible bool
+
+	GPUs             int
+	PlacementGroupID string
+
+	Labels   []string
+	Metadata map[string]string
+
+	AdditionalDisks []string
+
+	SerialLogFile string
+
+	InstanceID string
+}
+
+// NewDriver creates and returns a new Yandex.Cloud driver with default settings.
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		Zone:          defaultZone,
+		PlatformID:    defaultPlatformID,
+		Cores:         defaultCores,
+		CoreFraction:  defaultCoreFraction,
+		Memory:        defaultMemory,
+		DiskType:      defaultDiskType,
+		DiskSize:      defaultDiskSize,
+		ImageFamily:   defaultImageFamily,
+		ImageFolderID: defaultImageFolderID,
+		SubnetCIDR:    defaultSubnetCIDR,
+		Nat:           true,
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     defaultSSHUser,
+			SSHPort:     defaultSSHPort,
+		},
+	}
+}
+
+func (d *Driver) DriverName() string {
+	return "yandex"
+}
+
+func (d *Driver) GetCreateFlags() []mcnflag.Flag {
+	return []mcnflag.Flag{
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_TOKEN",
+			Name:   "yandex-token",
+			Usage:  "OAuth token for Yandex.Cloud API",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_SA_KEY_FILE",
+			Name:   "yandex-sa-key-file",
+			Usage:  "Path to the service account key file",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_ENDPOINT",
+			Name:   "yandex-endpoint",
+			Usage:  "Yandex.Cloud API endpoint",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_FOLDER_ID",
+			Name:   "yandex-folder-id",
+			Usage:  "The ID of the folder to create the instance in",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_ZONE",
+			Name:   "yandex-zone",
+			Usage:  "The zone to launch the instance into",
+			Value:  defaultZone,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_PLATFORM_ID",
+			Name:   "yandex-platform-id",
+			Usage:  "The platform to launch the instance with",
+			Value:  defaultPlatformID,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_CORES",
+			Name:   "yandex-cores",
+			Usage:  "The number of CPU cores for the instance",
+			Value:  defaultCores,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_CORE_FRACTION",
+			Name:   "yandex-core-fraction",
+			Usage:  "Guaranteed vCPU share, in percent",
+			Value:  defaultCoreFraction,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_MEMORY",
+			Name:   "yandex-memory",
+			Usage:  "The amount of memory for the instance, in GB",
+			Value:  defaultMemory,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_DISK_TYPE",
+			Name:   "yandex-disk-type",
+			Usage:  "The boot disk type for the instance",
+			Value:  defaultDiskType,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_DISK_SIZE",
+			Name:   "yandex-disk-size",
+			Usage:  "The boot disk size for the instance, in GB",
+			Value:  defaultDiskSize,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_IMAGE_ID",
+			Name:   "yandex-image-id",
+			Usage:  "The ID of the image to create the boot disk from",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_IMAGE_FAMILY",
+			Name:   "yandex-image-family",
+			Usage:  "The family of the image to create the boot disk from",
+			Value:  defaultImageFamily,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_IMAGE_FOLDER_ID",
+			Name:   "yandex-image-folder-id",
+			Usage:  "The ID of the folder to look the image up in",
+			Value:  defaultImageFolderID,
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_SUBNET_ID",
+			Name:   "yandex-subnet-id",
+			Usage:  "The ID of the subnet to attach the instance to. If empty, an ephemeral network and subnet are created and torn down with the instance",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_SUBNET_CIDR",
+			Name:   "yandex-subnet-cidr",
+			Usage:  "The IPv4 CIDR block to use for the ephemeral subnet created when --yandex-subnet-id is empty",
+			Value:  defaultSubnetCIDR,
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "YANDEX_NAT",
+			Name:   "yandex-nat",
+			Usage:  "Assign a public NAT address to the instance",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_ADDRESS",
+			Name:   "yandex-address",
+			Usage:  "The ID of an already-reserved static public address to assign to the instance",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "YANDEX_RESERVE_STATIC_ADDRESS",
+			Name:   "yandex-reserve-static-address",
+			Usage:  "Reserve a new static public address for the instance instead of an ephemeral one",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "YANDEX_USE_IPV6",
+			Name:   "yandex-use-ipv6",
+			Usage:  "Use the instance's IPv6 address for the docker-machine connection",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "YANDEX_USE_INTERNAL_IP",
+			Name:   "yandex-use-internal-ip",
+			Usage:  "Use the instance's internal IP address for the docker-machine connection",
+		},
+		mcnflag.BoolFlag{
+			EnvVar: "YANDEX_PREEMPTIBLE",
+			Name:   "yandex-preemptible",
+			Usage:  "Create a preemptible instance",
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_GPUS",
+			Name:   "yandex-gpus",
+			Usage:  "The number of GPUs to attach to the instance; requires a gpu-* platform",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_PLACEMENT_GROUP_ID",
+			Name:   "yandex-placement-group-id",
+			Usage:  "The ID of the placement group to schedule the instance into, for anti-affinity clusters",
+		},
+		mcnflag.StringSliceFlag{
+			EnvVar: "YANDEX_LABELS",
+			Name:   "yandex-labels",
+			Usage:  "Instance labels, in key=value form",
+		},
+		mcnflag.StringSliceFlag{
+			EnvVar: "YANDEX_ADDITIONAL_DISK",
+			Name:   "yandex-additional-disk",
+			Usage:  "Additional disk to attach, either \"disk-id=<id>\" or \"type=<type>,size=<gb>,name=<name>\" (repeatable)",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_SERIAL_LOG_FILE",
+			Name:   "yandex-serial-log-file",
+			Usage:  "If set, persist the instance's serial console output here when provisioning fails",
+		},
+		mcnflag.StringFlag{
+			EnvVar: "YANDEX_SSH_USER",
+			Name:   "yandex-ssh-user",
+			Usage:  "SSH user to connect to the instance with",
+			Value:  defaultSSHUser,
+		},
+		mcnflag.IntFlag{
+			EnvVar: "YANDEX_SSH_PORT",
+			Name:   "yandex-ssh-port",
+			Usage:  "SSH port to connect to the instance on",
+			Value:  defaultSSHPort,
+		},
+	}
+}
+
+func (d *Driver) SetConfigFromFlags(flags drivers.DriverOptions) error {
+	d.Token = flags.String("yandex-token")
+	d.ServiceAccountKeyFile = flags.String("yandex-sa-key-file")
+	d.Endpoint = flags.String("yandex-endpoint")
+
+	d.FolderID = flags.String("yandex-folder-id")
+	d.Zone = flags.String("yandex-zone")
+	d.PlatformID = flags.String("yandex-platform-id")
+	d.Cores = flags.Int("yandex-cores")
+	d.CoreFraction = flags.Int("yandex-core-fraction")
+	d.Memory = flags.Int("yandex-memory")
+	d.DiskType = flags.String("yandex-disk-type")
+	d.DiskSize = flags.Int("yandex-disk-size")
+	d.ImageID = flags.String("yandex-image-id")
+	d.ImageFamily = flags.String("yandex-image-family")
+	d.ImageFolderID = flags.String("yandex-image-folder-id")
+
+	d.SubnetID = flags.String("yandex-subnet-id")
+	d.SubnetCIDR = flags.String("yandex-subnet-cidr")
+	d.Nat = flags.Bool("yandex-nat")
+	d.UseIPv6 = flags.Bool("yandex-use-ipv6")
+	d.UseInternalIP = flags.Bool("yandex-use-internal-ip")
+
+	d.Address = flags.String("yandex-address")
+	d.ReserveStaticAddress = flags.Bool("yandex-reserve-static-address")
+
+	if d.Address != "" && d.ReserveStaticAddress {
+		return fmt.Errorf("only one of --yandex-address or --yandex-reserve-static-address may be set")
+	}
+
+	d.Preemptible = flags.Bool("yandex-preemptible")
+
+	d.GPUs = flags.Int("yandex-gpus")
+	d.PlacementGroupID = flags.String("yandex-placement-group-id")
+
+	d.Labels = flags.StringSlice("yandex-labels")
+	d.AdditionalDisks = flags.StringSlice("yandex-additional-disk")
+	d.SerialLogFile = flags.String("yandex-serial-log-file")
+
+	d.SSHUser = flags.String("yandex-ssh-user")
+	d.SSHPort = flags.Int("yandex-ssh-port")
+
+	d.SetSwarmConfigFromFlags(flags)
+
+	if d.FolderID == "" {
+		return fmt.Errorf("yandex driver requires the --yandex-folder-id option")
+	}
+
+	// Credentials may also come from YC_TOKEN, YC_SERVICE_ACCOUNT_KEY_FILE, or
+	// instance service account metadata; resolveCredentials is the source of
+	// truth and falls back to the instance service account when none of the
+	// flags or environment variables is set.
+
+	return nil
+}
+
+// ParsedLabels turns the repeatable --yandex-labels key=value flag into a map.
+func (d *Driver) ParsedLabels() map[string]string {
+	labels := make(map[string]string, len(d.Labels))
+	for _, kv := range d.Labels {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels
+}
+
+// ParsedAdditionalDisks turns the repeatable --yandex-additional-disk flag into secondary
+// disk specs. Each entry is either "disk-id=<id>" to attach an existing disk, or a
+// comma-separated "type=<type>,size=<gb>,name=<name>" to create a new one.
+func (d *Driver) ParsedAdditionalDisks() ([]*compute.AttachedDiskSpec, error) {
+	specs := make([]*compute.AttachedDiskSpec, 0, len(d.AdditionalDisks))
+
+	for _, raw := range d.AdditionalDisks {
+		fields := make(map[string]string)
+		for _, kv := range strings.Split(raw, ",") {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid --yandex-additional-disk entry %q: expected key=value pairs", raw)
+			}
+			fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+
+		if diskID, ok := fields["disk-id"]; ok {
+			specs = append(specs, &compute.AttachedDiskSpec{
+				AutoDelete: false,
+				Disk: &compute.AttachedDiskSpec_DiskId{
+					DiskId: diskID,
+				},
+			})
+			continue
+		}
+
+		diskType := fields["type"]
+		if diskType == "" {
+			diskType = d.DiskType
+		}
+
+		size := fields["size"]
+		if size == "" {
+			return nil, fmt.Errorf("invalid --yandex-additional-disk entry %q: missing size", raw)
+		}
+
+		sizeGB, err := strconv.Atoi(size)
+		if err != nil || sizeGB <= 0 {
+			return nil, fmt.Errorf("invalid --yandex-additional-disk entry %q: size must be a positive integer number of GB", raw)
+		}
+
+		specs = append(specs, &compute.AttachedDiskSpec{
+			AutoDelete: true,
+			Disk: &compute.AttachedDiskSpec_DiskSpec_{
+				DiskSpec: &compute.AttachedDiskSpec_DiskSpec{
+					Name:   fields["name"],
+					TypeId: diskType,
+					Size:   toBytes(sizeGB),
+				},
+			},
+		})
+	}
+
+	return specs, nil
+}
+
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+func (d *Driver) GetURL() (string, error) {
+	if err := drivers.MustBeRunning(d); err != nil {
+		return "", err
+	}
+
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, "2376")), nil
+}
+
+func (d *Driver) GetState() (state.State, error) {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return state.Error, err
+	}
+
+	log.Debugf("Using credentials resolved from %s", client.CredentialsSource)
+
+	instance, err := client.getInstance(d)
+	if err != nil {
+		return state.Error, err
+	}
+
+	switch instance.Status {
+	case compute.Instance_RUNNING:
+		return state.Running, nil
+	case compute.Instance_STOPPED:
+		return state.Stopped, nil
+	case compute.Instance_STOPPING:
+		return state.Stopping, nil
+	case compute.Instance_STARTING, compute.Instance_PROVISIONING, compute.Instance_RESTARTING, compute.Instance_UPDATING:
+		return state.Starting, nil
+	case compute.Instance_ERROR:
+		return state.Error, nil
+	default:
+		return state.None, nil
+	}
+}
+
+// DebugSerialConsole fetches the instance's serial console output and returns
+// it, also writing it to --yandex-serial-log-file when that is set. No command
+// of this driver calls it; createInstance's own error path captures the log
+// through captureSerialLog instead. docker-machine's own SSH-reachability
+// retries happen inside libmachine's provisioning code, outside of any Driver
+// hook, so an SSH timeout there does not capture the serial log.
+func (d *Driver) DebugSerialConsole() (string, error) {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := client.fetchSerialConsoleOutput(d)
+	if err != nil {
+		return "", err
+	}
+
+	if d.SerialLogFile != "" {
+		if err := os.WriteFile(d.SerialLogFile, []byte(output), 0644); err != nil {
+			return output, err
+		}
+	}
+
+	return output, nil
+}
+
+func (d *Driver) Create() error {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return err
+	}
+
+	return client.createInstance(d)
+}
+
+func (d *Driver) Remove() error {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return err
+	}
+
+	return client.removeInstance(d)
+}
+
+func (d *Driver) Start() error {
+	if d.Preemptible {
+		return d.EnsureRunning()
+	}
+
+	client, err := NewYCClient(d)
+	if err != nil {
+		return err
+	}
+
+	return client.startInstance(d)
+}
+
+func (d *Driver) Stop() error {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return err
+	}
+
+	return client.stopInstance(d)
+}
+
+func (d *Driver) Restart() error {
+	if err := d.Stop(); err != nil {
+		return err
+	}
+
+	return d.Start()
+}
+
+func (d *Driver) Kill() error {
+	return d.Stop()
+}
+
+func (d *Driver) PreCreateCheck() error {
+	if d.SubnetID == "" {
+		log.Info("No --yandex-subnet-id provided; an ephemeral network and subnet will be created and removed with the instance")
+	}
+
+	return validateSpec(d)
+}
+
+// validateSpec catches instance spec combinations the Yandex.Cloud API would
+// otherwise reject, so users get an actionable error before Create() ever
+// makes a request.
+func validateSpec(d *Driver) error {
+	if !d.Nat && (d.Address != "" || d.ReserveStaticAddress) {
+		return fmt.Errorf("--yandex-address and --yandex-reserve-static-address require --yandex-nat: there is no NAT interface to attach the address to")
+	}
+
+	if d.GPUs < 0 {
+		return fmt.Errorf("--yandex-gpus must not be negative")
+	}
+
+	if d.GPUs > 0 {
+		if d.Preemptible {
+			return fmt.Errorf("--yandex-gpus is not supported together with --yandex-preemptible: Yandex.Cloud does not offer preemptible GPU instances")
+		}
+
+		if !strings.HasPrefix(d.PlatformID, "gpu-") {
+			return fmt.Errorf("--yandex-gpus requires a GPU platform (e.g. --yandex-platform-id=gpu-standard-v1), got %q", d.PlatformID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package driver
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+)
+
+// EnsureRunning polls the instance's current status and restarts it if it
+// has been stopped. Yandex.Cloud preemptible instances are reclaimed within
+// 24 hours and left in a STOPPED state rather than being deleted, so callers
+// that want preemptible instances to recover on their own should invoke this
+// periodically (docker-machine itself only calls Start() once).
+func (d *Driver) EnsureRunning() error {
+	client, err := NewYCClient(d)
+	if err != nil {
+		return err
+	}
+
+	return client.ensureRunning(d)
+}
+
+// instanceStatusClient is the subset of YCClient that ensureRunning needs,
+// split out so the restart logic can be exercised against a mock in tests.
+type instanceStatusClient interface {
+	getInstance(d *Driver) (*compute.Instance, error)
+	startInstance(d *Driver) error
+}
+
+func (c *YCClient) ensureRunning(d *Driver) error {
+	return ensureRunning(c, d)
+}
+
+func ensureRunning(c instanceStatusClient, d *Driver) error {
+	instance, err := c.getInstance(d)
+	if err != nil {
+		return fmt.Errorf("Error while polling instance status: %s", err)
+	}
+
+	switch instance.Status {
+	case compute.Instance_RUNNING:
+		return nil
+	case compute.Instance_STOPPED:
+		log.Infof("Instance %q is stopped; restarting", d.InstanceID)
+		return c.startInstance(d)
+	default:
+		return nil
+	}
+}
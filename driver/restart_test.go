@@ -0,0 +1,67 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
+)
+
+type mockInstanceStatusClient struct {
+	instance    *compute.Instance
+	getErr      error
+	startErr    error
+	startCalled bool
+}
+
+func (m *mockInstanceStatusClient) getInstance(d *Driver) (*compute.Instance, error) {
+	return m.instance, m.getErr
+}
+
+func (m *mockInstanceStatusClient) startInstance(d *Driver) error {
+	m.startCalled = true
+	return m.startErr
+}
+
+func TestEnsureRunningStartsStoppedInstance(t *testing.T) {
+	mock := &mockInstanceStatusClient{
+		instance: &compute.Instance{Status: compute.Instance_STOPPED},
+	}
+
+	if err := ensureRunning(mock, &Driver{InstanceID: "fake-id"}); err != nil {
+		t.Fatalf("ensureRunning returned an error: %s", err)
+	}
+
+	if !mock.startCalled {
+		t.Error("expected startInstance to be called for a STOPPED instance")
+	}
+}
+
+func TestEnsureRunningLeavesRunningInstanceAlone(t *testing.T) {
+	mock := &mockInstanceStatusClient{
+		instance: &compute.Instance{Status: compute.Instance_RUNNING},
+	}
+
+	if err := ensureRunning(mock, &Driver{InstanceID: "fake-id"}); err != nil {
+		t.Fatalf("ensureRunning returned an error: %s", err)
+	}
+
+	if mock.startCalled {
+		t.Error("did not expect startInstance to be called for a RUNNING instance")
+	}
+}
+
+func TestEnsureRunningPropagatesGetInstanceError(t *testing.T) {
+	mock := &mockInstanceStatusClient{
+		getErr: errors.New("boom"),
+	}
+
+	err := ensureRunning(mock, &Driver{InstanceID: "fake-id"})
+	if err == nil {
+		t.Fatal("expected an error when getInstance fails")
+	}
+
+	if mock.startCalled {
+		t.Error("did not expect startInstance to be called when getInstance fails")
+	}
+}
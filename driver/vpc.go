@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+)
+
+// ensureNetworking makes sure the instance has somewhere to attach a network
+// interface. If the user did not supply --yandex-subnet-id, an ephemeral
+// network and subnet are created in the target zone and recorded on the
+// Driver so Remove() can tear them down again.
+func (c *YCClient) ensureNetworking(d *Driver) error {
+	if d.SubnetID != "" {
+		return nil
+	}
+
+	networkID, err := c.createNetwork(d)
+	if err != nil {
+		return fmt.Errorf("Error while creating network: %s", err)
+	}
+	d.CreatedNetworkID = networkID
+
+	subnetID, err := c.createSubnet(d, networkID)
+	if err != nil {
+		return fmt.Errorf("Error while creating subnet: %s", err)
+	}
+	d.CreatedSubnetID = subnetID
+
+	d.SubnetID = subnetID
+
+	return nil
+}
+
+func (c *YCClient) createNetwork(d *Driver) (string, error) {
+	ctx := context.Background()
+
+	name := d.MachineName + "-network"
+	log.Infof("Creating ephemeral VPC network %q", name)
+
+	op, err := c.sdk.WrapOperation(c.sdk.VPC().Network().Create(ctx, &vpc.CreateNetworkRequest{
+		FolderId: d.FolderID,
+		Name:     name,
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	protoMetadata, err := op.Metadata()
+	if err != nil {
+		return "", err
+	}
+
+	md, ok := protoMetadata.(*vpc.CreateNetworkMetadata)
+	if !ok {
+		return "", fmt.Errorf("could not get Network ID from create operation metadata")
+	}
+
+	if err = op.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	return md.NetworkId, nil
+}
+
+func (c *YCClient) createSubnet(d *Driver, networkID string) (string, error) {
+	ctx := context.Background()
+
+	name := d.MachineName + "-subnet"
+	log.Infof("Creating ephemeral VPC subnet %q with CIDR %q in zone %q", name, d.SubnetCIDR, d.Zone)
+
+	op, err := c.sdk.WrapOperation(c.sdk.VPC().Subnet().Create(ctx, &vpc.CreateSubnetRequest{
+		FolderId:     d.FolderID,
+		Name:         name,
+		NetworkId:    networkID,
+		ZoneId:       d.Zone,
+		V4CidrBlocks: []string{d.SubnetCIDR},
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	protoMetadata, err := op.Metadata()
+	if err != nil {
+		return "", err
+	}
+
+	md, ok := protoMetadata.(*vpc.CreateSubnetMetadata)
+	if !ok {
+		return "", fmt.Errorf("could not get Subnet ID from create operation metadata")
+	}
+
+	if err = op.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	return md.SubnetId, nil
+}
+
+// removeNetworking tears down any network and subnet the driver created for
+// this instance. It is a no-op for networking the user supplied, and is safe
+// to call more than once since it clears the IDs it removes.
+func (c *YCClient) removeNetworking(d *Driver) error {
+	ctx := context.Background()
+
+	if d.CreatedSubnetID != "" {
+		log.Infof("Removing ephemeral VPC subnet %q", d.CreatedSubnetID)
+
+		op, err := c.sdk.WrapOperation(c.sdk.VPC().Subnet().Delete(ctx, &vpc.DeleteSubnetRequest{
+			SubnetId: d.CreatedSubnetID,
+		}))
+		if err != nil {
+			return fmt.Errorf("Error while removing subnet: %s", err)
+		}
+		if err = op.Wait(ctx); err != nil {
+			return fmt.Errorf("Error while removing subnet: %s", err)
+		}
+
+		d.CreatedSubnetID = ""
+	}
+
+	if d.CreatedNetworkID != "" {
+		log.Infof("Removing ephemeral VPC network %q", d.CreatedNetworkID)
+
+		op, err := c.sdk.WrapOperation(c.sdk.VPC().Network().Delete(ctx, &vpc.DeleteNetworkRequest{
+			NetworkId: d.CreatedNetworkID,
+		}))
+		if err != nil {
+			return fmt.Errorf("Error while removing network: %s", err)
+		}
+		if err = op.Wait(ctx); err != nil {
+			return fmt.Errorf("Error while removing network: %s", err)
+		}
+
+		d.CreatedNetworkID = ""
+	}
+
+	return nil
+}